@@ -0,0 +1,169 @@
+package workqueue
+
+import "time"
+
+// waiter parks a single caller until ch is closed.
+type waiter struct {
+	ch chan struct{}
+}
+
+// isFullLocked reports whether the queue is at capacity. The caller must
+// hold q.cond.L.
+func (q *FIFO[T]) isFullLocked() bool {
+	return q.capacity > 0 && q.storage.Len()+q.processing.len() >= q.capacity
+}
+
+// newAddWaiterLocked registers a new producer waiter at the back of the
+// line. The caller must hold q.cond.L.
+func (q *FIFO[T]) newAddWaiterLocked() *waiter {
+	w := &waiter{ch: make(chan struct{})}
+	q.addWaiters = append(q.addWaiters, w)
+	return w
+}
+
+// removeAddWaiterLocked removes w from the waiting producers, if still
+// present, and reports whether it was found there. A waiter that is no
+// longer present was already woken by wakeNextAddWaiterLocked, which means
+// it was granted a capacity slot. The caller must hold q.cond.L.
+func (q *FIFO[T]) removeAddWaiterLocked(w *waiter) bool {
+	for i, cand := range q.addWaiters {
+		if cand == w {
+			q.addWaiters = append(q.addWaiters[:i], q.addWaiters[i+1:]...)
+			return true
+		}
+	}
+	return false
+}
+
+// wakeNextAddWaiterLocked wakes the longest-waiting producer, if there is
+// room for it. The caller must hold q.cond.L.
+func (q *FIFO[T]) wakeNextAddWaiterLocked() {
+	if len(q.addWaiters) == 0 || q.isFullLocked() {
+		return
+	}
+	w := q.addWaiters[0]
+	q.addWaiters = q.addWaiters[1:]
+	close(w.ch)
+}
+
+// newGetWaiterLocked registers a new consumer waiter at the back of the
+// line. The caller must hold q.cond.L.
+func (q *FIFO[T]) newGetWaiterLocked() *waiter {
+	w := &waiter{ch: make(chan struct{})}
+	q.getWaiters = append(q.getWaiters, w)
+	return w
+}
+
+// removeGetWaiterLocked removes w from the waiting consumers, if still
+// present. The caller must hold q.cond.L.
+func (q *FIFO[T]) removeGetWaiterLocked(w *waiter) {
+	for i, cand := range q.getWaiters {
+		if cand == w {
+			q.getWaiters = append(q.getWaiters[:i], q.getWaiters[i+1:]...)
+			return
+		}
+	}
+}
+
+// wakeNextGetWaiterLocked wakes the longest-waiting consumer, if there is an
+// item for it. The caller must hold q.cond.L.
+func (q *FIFO[T]) wakeNextGetWaiterLocked() {
+	if len(q.getWaiters) == 0 || q.storage.Len() == 0 {
+		return
+	}
+	w := q.getWaiters[0]
+	q.getWaiters = q.getWaiters[1:]
+	close(w.ch)
+}
+
+// AddWithTimeout behaves like Add, but gives up and returns ErrFull if the
+// queue is still at capacity after d, or ErrShutDown if the queue is shut
+// down while waiting.
+func (q *FIFO[T]) AddWithTimeout(item T, d time.Duration) error {
+	q.cond.L.Lock()
+
+	if q.shuttingDown {
+		q.cond.L.Unlock()
+		return ErrShutDown
+	}
+	if q.dirty.has(item) || !q.isFullLocked() {
+		q.addLocked(item)
+		q.cond.L.Unlock()
+		return nil
+	}
+
+	w := q.newAddWaiterLocked()
+	q.cond.L.Unlock()
+
+	timer := q.clock.NewTimer(d)
+	defer timer.Stop()
+
+	select {
+	case <-w.ch:
+		q.cond.L.Lock()
+		defer q.cond.L.Unlock()
+		if q.shuttingDown {
+			return ErrShutDown
+		}
+		q.addLocked(item)
+		return nil
+	case <-timer.C():
+		q.cond.L.Lock()
+		defer q.cond.L.Unlock()
+		if !q.removeAddWaiterLocked(w) {
+			// w was already woken by wakeNextAddWaiterLocked before the
+			// timer fired: it was granted the freed slot, so honor that
+			// instead of dropping it on the floor and stalling whoever
+			// waits behind w.
+			if q.shuttingDown {
+				return ErrShutDown
+			}
+			q.addLocked(item)
+			return nil
+		}
+		return ErrFull
+	}
+}
+
+// GetWithTimeout behaves like Get, but gives up and returns ErrTimeout if no
+// item becomes available within d.
+func (q *FIFO[T]) GetWithTimeout(d time.Duration) (item T, shutdown bool, err error) {
+	q.cond.L.Lock()
+
+	if q.storage.Len() > 0 || q.shuttingDown {
+		item, shutdown = q.getLocked()
+		q.cond.L.Unlock()
+		return item, shutdown, nil
+	}
+
+	w := q.newGetWaiterLocked()
+	q.cond.L.Unlock()
+
+	timer := q.clock.NewTimer(d)
+	defer timer.Stop()
+
+	for {
+		select {
+		case <-w.ch:
+			q.cond.L.Lock()
+			if q.storage.Len() == 0 && !q.shuttingDown {
+				// Whatever woke w was claimed by a concurrent Get/
+				// GetWithTimeout before we got the lock back; the queue
+				// is merely empty, not shutting down, so re-park instead
+				// of letting getLocked misreport shutdown=true.
+				w = q.newGetWaiterLocked()
+				q.cond.L.Unlock()
+				continue
+			}
+			item, shutdown = q.getLocked()
+			q.cond.L.Unlock()
+			return item, shutdown, nil
+		case <-timer.C():
+			q.cond.L.Lock()
+			defer q.cond.L.Unlock()
+			q.removeGetWaiterLocked(w)
+			var noop T
+			return noop, false, ErrTimeout
+		}
+	}
+}
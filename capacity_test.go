@@ -0,0 +1,163 @@
+package workqueue
+
+import (
+	"sync"
+	"testing"
+	"time"
+
+	testingclock "k8s.io/utils/clock/testing"
+)
+
+func newCapacityTestQueue(c *testingclock.FakeClock, capacity int) *FIFO[string] {
+	q := newQueue[string](c, noMetrics[string]{}, time.Millisecond)
+	q.capacity = capacity
+	return q
+}
+
+func TestAddWithTimeoutExpiresWhenFull(t *testing.T) {
+	c := testingclock.NewFakeClock(time.Now())
+	q := newCapacityTestQueue(c, 1)
+	defer q.ShutDown()
+
+	q.Add("a")
+
+	errCh := make(chan error, 1)
+	go func() {
+		errCh <- q.AddWithTimeout("b", time.Millisecond)
+	}()
+
+	for !c.HasWaiters() {
+		time.Sleep(time.Millisecond)
+	}
+	c.Step(time.Millisecond)
+
+	if err := <-errCh; err != ErrFull {
+		t.Errorf("expected ErrFull, got %v", err)
+	}
+}
+
+func TestAddWithTimeoutSucceedsWhenSlotFreed(t *testing.T) {
+	c := testingclock.NewFakeClock(time.Now())
+	q := newCapacityTestQueue(c, 1)
+	defer q.ShutDown()
+
+	q.Add("a")
+
+	errCh := make(chan error, 1)
+	go func() {
+		errCh <- q.AddWithTimeout("b", time.Hour)
+	}()
+
+	for !c.HasWaiters() {
+		time.Sleep(time.Millisecond)
+	}
+
+	// Freeing the one slot via Done should grant it to the waiting
+	// AddWithTimeout call rather than letting it stall until some later,
+	// unrelated Done call.
+	item, _ := q.Get()
+	q.Done(item)
+
+	if err := <-errCh; err != nil {
+		t.Errorf("expected AddWithTimeout to succeed once its slot was granted, got %v", err)
+	}
+
+	got, shutdown := q.Get()
+	if shutdown || got != "b" {
+		t.Errorf("expected to receive %q, got %q (shutdown=%v)", "b", got, shutdown)
+	}
+}
+
+func TestGetWithTimeoutExpiresWhenEmpty(t *testing.T) {
+	c := testingclock.NewFakeClock(time.Now())
+	q := newCapacityTestQueue(c, 0)
+	defer q.ShutDown()
+
+	resultCh := make(chan error, 1)
+	go func() {
+		_, _, err := q.GetWithTimeout(time.Millisecond)
+		resultCh <- err
+	}()
+
+	for !c.HasWaiters() {
+		time.Sleep(time.Millisecond)
+	}
+	c.Step(time.Millisecond)
+
+	if err := <-resultCh; err != ErrTimeout {
+		t.Errorf("expected ErrTimeout, got %v", err)
+	}
+}
+
+func TestGetWithTimeoutReturnsItemBeforeDeadline(t *testing.T) {
+	c := testingclock.NewFakeClock(time.Now())
+	q := newCapacityTestQueue(c, 0)
+	defer q.ShutDown()
+
+	type result struct {
+		item     string
+		shutdown bool
+		err      error
+	}
+	resultCh := make(chan result, 1)
+	go func() {
+		item, shutdown, err := q.GetWithTimeout(time.Hour)
+		resultCh <- result{item, shutdown, err}
+	}()
+
+	for !c.HasWaiters() {
+		time.Sleep(time.Millisecond)
+	}
+	q.Add("x")
+
+	r := <-resultCh
+	if r.err != nil || r.shutdown || r.item != "x" {
+		t.Errorf("expected (%q, false, nil), got (%q, %v, %v)", "x", r.item, r.shutdown, r.err)
+	}
+}
+
+// TestGetWithTimeoutDoesNotReportShutdownOnStolenWakeup exercises the race
+// where a plain Get() steals the item that woke a parked GetWithTimeout
+// call: the queue is merely empty afterwards, not shutting down, so
+// GetWithTimeout must re-park rather than report shutdown=true.
+func TestGetWithTimeoutDoesNotReportShutdownOnStolenWakeup(t *testing.T) {
+	q := New[int]()
+	defer q.ShutDown()
+
+	var wg sync.WaitGroup
+	const rounds = 200
+	for i := 0; i < rounds; i++ {
+		wg.Add(2)
+		var sawShutdown bool
+		var mu sync.Mutex
+
+		go func() {
+			defer wg.Done()
+			item, shutdown, err := q.GetWithTimeout(10 * time.Millisecond)
+			if err == nil {
+				mu.Lock()
+				sawShutdown = sawShutdown || shutdown
+				mu.Unlock()
+				if !shutdown {
+					// GetWithTimeout got the item before the plain Get
+					// did; hand it back so that Get is not left blocked.
+					q.Done(item)
+					q.Add(item)
+				}
+			}
+		}()
+		go func() {
+			defer wg.Done()
+			q.Add(i)
+			q.Get()
+		}()
+
+		wg.Wait()
+		mu.Lock()
+		bad := sawShutdown
+		mu.Unlock()
+		if bad {
+			t.Fatalf("round %d: GetWithTimeout reported shutdown=true on a live queue", i)
+		}
+	}
+}
@@ -0,0 +1,150 @@
+package workqueue
+
+import (
+	"math"
+	"sync"
+	"time"
+
+	"golang.org/x/time/rate"
+)
+
+// RateLimiter is the interface a rate limiting queue uses to decide how long
+// an item should wait before being added back to the queue, following a
+// failed attempt to process it.
+type RateLimiter[T comparable] interface {
+	// When returns how long an item should wait.
+	When(item T) time.Duration
+	// Forget indicates that an item is finished being retried. Doesn't
+	// matter whether it's for perm failing or for success, we'll stop
+	// tracking it.
+	Forget(item T)
+	// NumRequeues returns back how many failures the item has had.
+	NumRequeues(item T) int
+}
+
+// DefaultControllerRateLimiter is a no-arg constructor for a default rate
+// limiter for a workqueue. Per-item exponential backoff, up to 1000s,
+// capped at 10 qps and a 100 item burst.
+func DefaultControllerRateLimiter[T comparable]() RateLimiter[T] {
+	return NewMaxOfRateLimiter(
+		NewItemExponentialFailureRateLimiter[T](5*time.Millisecond, 1000*time.Second),
+		&BucketRateLimiter[T]{Limiter: rate.NewLimiter(rate.Limit(10), 100)},
+	)
+}
+
+// ItemExponentialFailureRateLimiter tracks failures in a map until Forget is
+// called, doubling the delay from baseDelay up to maxDelay each time.
+type ItemExponentialFailureRateLimiter[T comparable] struct {
+	failuresLock sync.Mutex
+	failures     map[T]int
+
+	baseDelay time.Duration
+	maxDelay  time.Duration
+}
+
+var _ RateLimiter[string] = &ItemExponentialFailureRateLimiter[string]{}
+
+// NewItemExponentialFailureRateLimiter constructs a rate limiter that starts
+// at baseDelay and doubles with each failure of a given item, up to maxDelay.
+func NewItemExponentialFailureRateLimiter[T comparable](baseDelay time.Duration, maxDelay time.Duration) RateLimiter[T] {
+	return &ItemExponentialFailureRateLimiter[T]{
+		failures:  map[T]int{},
+		baseDelay: baseDelay,
+		maxDelay:  maxDelay,
+	}
+}
+
+func (r *ItemExponentialFailureRateLimiter[T]) When(item T) time.Duration {
+	r.failuresLock.Lock()
+	defer r.failuresLock.Unlock()
+
+	exp := r.failures[item]
+	r.failures[item] = exp + 1
+
+	// The backoff is capped such that 'calculated' value never overflows.
+	backoff := float64(r.baseDelay.Nanoseconds()) * math.Pow(2, float64(exp))
+	if backoff > math.MaxInt64 {
+		return r.maxDelay
+	}
+
+	calculated := time.Duration(backoff)
+	if calculated > r.maxDelay {
+		return r.maxDelay
+	}
+
+	return calculated
+}
+
+func (r *ItemExponentialFailureRateLimiter[T]) NumRequeues(item T) int {
+	r.failuresLock.Lock()
+	defer r.failuresLock.Unlock()
+
+	return r.failures[item]
+}
+
+func (r *ItemExponentialFailureRateLimiter[T]) Forget(item T) {
+	r.failuresLock.Lock()
+	defer r.failuresLock.Unlock()
+
+	delete(r.failures, item)
+}
+
+// BucketRateLimiter adapts a token bucket limiter from golang.org/x/time/rate
+// to the RateLimiter interface. The token bucket is shared across all items,
+// so it limits the overall rate of additions rather than per-item backoff.
+type BucketRateLimiter[T comparable] struct {
+	*rate.Limiter
+}
+
+var _ RateLimiter[string] = &BucketRateLimiter[string]{}
+
+func (r *BucketRateLimiter[T]) When(item T) time.Duration {
+	return r.Limiter.Reserve().Delay()
+}
+
+func (r *BucketRateLimiter[T]) NumRequeues(item T) int {
+	return 0
+}
+
+func (r *BucketRateLimiter[T]) Forget(item T) {
+}
+
+// MaxOfRateLimiter calls every RateLimiter and returns the worst-case
+// (longest) delay. This is useful when you have a set of limiters that you
+// want to combine, such as an overall rate limiter plus a per-item backoff.
+type MaxOfRateLimiter[T comparable] struct {
+	limiters []RateLimiter[T]
+}
+
+func (r *MaxOfRateLimiter[T]) When(item T) time.Duration {
+	ret := time.Duration(0)
+	for _, limiter := range r.limiters {
+		if curr := limiter.When(item); curr > ret {
+			ret = curr
+		}
+	}
+
+	return ret
+}
+
+// NewMaxOfRateLimiter constructs a MaxOfRateLimiter from the given limiters.
+func NewMaxOfRateLimiter[T comparable](limiters ...RateLimiter[T]) RateLimiter[T] {
+	return &MaxOfRateLimiter[T]{limiters: limiters}
+}
+
+func (r *MaxOfRateLimiter[T]) NumRequeues(item T) int {
+	ret := 0
+	for _, limiter := range r.limiters {
+		if curr := limiter.NumRequeues(item); curr > ret {
+			ret = curr
+		}
+	}
+
+	return ret
+}
+
+func (r *MaxOfRateLimiter[T]) Forget(item T) {
+	for _, limiter := range r.limiters {
+		limiter.Forget(item)
+	}
+}
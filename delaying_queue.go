@@ -0,0 +1,230 @@
+package workqueue
+
+import (
+	"container/heap"
+	"sync"
+	"time"
+
+	"k8s.io/utils/clock"
+)
+
+// defaultUnfinishedWorkUpdatePeriod is the unfinished work update period used
+// by queues constructed without an explicit one.
+const defaultUnfinishedWorkUpdatePeriod = 500 * time.Millisecond
+
+// DelayingInterface is a type of Interface that can Add an item at a later
+// time. This makes it easier to requeue items after failures without ending
+// up in a hot-loop.
+type DelayingInterface[T comparable] interface {
+	Interface[T]
+	// AddAfter adds an item to the workqueue after the indicated duration has passed.
+	AddAfter(item T, duration time.Duration)
+}
+
+// NewDelayingQueue constructs a new workqueue with delayed queuing ability.
+func NewDelayingQueue[T comparable]() DelayingInterface[T] {
+	return newDelayingQueue[T](clock.RealClock{})
+}
+
+func newDelayingQueue[T comparable](c clock.WithTicker) *delayingType[T] {
+	q := &delayingType[T]{
+		FIFO:            newQueue[T](c, noMetrics[T]{}, defaultUnfinishedWorkUpdatePeriod),
+		clock:           c,
+		heartbeat:       c.NewTicker(maxWait),
+		stopCh:          make(chan struct{}),
+		waitingForAddCh: make(chan *waitFor[T], 1000),
+	}
+
+	go q.waitingLoop()
+
+	return q
+}
+
+// maxWait keeps a check on the amount of time the delaying queue's
+// background goroutine will sleep for even if there are no waiting items.
+const maxWait = 10 * time.Second
+
+// delayingType wraps a FIFO and provides delayed re-enqueuing.
+type delayingType[T comparable] struct {
+	*FIFO[T]
+
+	clock clock.Clock
+
+	// stopCh lets us signal a shutdown to the waiting loop
+	stopCh chan struct{}
+	// stopOnce guarantees we only signal shutdown a single time
+	stopOnce sync.Once
+
+	// heartbeat ensures we wait no more than maxWait before firing
+	heartbeat clock.Ticker
+
+	// waitingForAddCh is a buffered channel that feeds waitingForAdd
+	waitingForAddCh chan *waitFor[T]
+}
+
+// waitFor holds the data to add and the time it should be added.
+type waitFor[T comparable] struct {
+	data    T
+	readyAt time.Time
+	// index is needed by the heap.Interface implementation so that we can
+	// reduce duplication in the heap operations.
+	index int
+}
+
+// waitForPriorityQueue implements heap.Interface, ordered on readyAt so the
+// item with the earliest readyAt is at the root.
+type waitForPriorityQueue[T comparable] []*waitFor[T]
+
+func (pq waitForPriorityQueue[T]) Len() int {
+	return len(pq)
+}
+
+func (pq waitForPriorityQueue[T]) Less(i, j int) bool {
+	return pq[i].readyAt.Before(pq[j].readyAt)
+}
+
+func (pq waitForPriorityQueue[T]) Swap(i, j int) {
+	pq[i], pq[j] = pq[j], pq[i]
+	pq[i].index = i
+	pq[j].index = j
+}
+
+func (pq *waitForPriorityQueue[T]) Push(x interface{}) {
+	n := len(*pq)
+	item := x.(*waitFor[T])
+	item.index = n
+	*pq = append(*pq, item)
+}
+
+func (pq *waitForPriorityQueue[T]) Pop() interface{} {
+	n := len(*pq)
+	item := (*pq)[n-1]
+	(*pq)[n-1] = nil
+	item.index = -1
+	*pq = (*pq)[:n-1]
+	return item
+}
+
+// Peek returns the item at the root, without removing it.
+func (pq waitForPriorityQueue[T]) Peek() interface{} {
+	return pq[0]
+}
+
+// ShutDown stops the delaying queue's background goroutine in addition to
+// the underlying FIFO's shutdown.
+func (q *delayingType[T]) ShutDown() {
+	q.stopOnce.Do(func() {
+		q.FIFO.ShutDown()
+		close(q.stopCh)
+		q.heartbeat.Stop()
+	})
+}
+
+// AddAfter adds the given item to the work queue after the given duration has
+// passed. If duration is non-positive, the item is added immediately.
+func (q *delayingType[T]) AddAfter(item T, duration time.Duration) {
+	if q.ShuttingDown() {
+		return
+	}
+
+	if duration <= 0 {
+		q.Add(item)
+		return
+	}
+
+	select {
+	case <-q.stopCh:
+	case q.waitingForAddCh <- &waitFor[T]{data: item, readyAt: q.clock.Now().Add(duration)}:
+	}
+}
+
+// waitingLoop runs until the workqueue is shut down, and watches the waiting
+// queue, pushing items onto the underlying FIFO as they become ready.
+func (q *delayingType[T]) waitingLoop() {
+	never := make(<-chan time.Time)
+	var nextReadyAtTimer clock.Timer
+
+	waitingForQueue := &waitForPriorityQueue[T]{}
+	heap.Init(waitingForQueue)
+
+	waitingEntryByData := map[T]*waitFor[T]{}
+
+	for {
+		if q.FIFO.ShuttingDown() {
+			return
+		}
+
+		now := q.clock.Now()
+
+		// Add ready entries to the underlying queue.
+		for waitingForQueue.Len() > 0 {
+			entry := waitingForQueue.Peek().(*waitFor[T])
+			if entry.readyAt.After(now) {
+				break
+			}
+
+			entry = heap.Pop(waitingForQueue).(*waitFor[T])
+			q.Add(entry.data)
+			delete(waitingEntryByData, entry.data)
+		}
+
+		// Set up a wait for the first item's readyAt, if there is one.
+		nextReadyAt := never
+		if waitingForQueue.Len() > 0 {
+			if nextReadyAtTimer != nil {
+				nextReadyAtTimer.Stop()
+			}
+			entry := waitingForQueue.Peek().(*waitFor[T])
+			nextReadyAtTimer = q.clock.NewTimer(entry.readyAt.Sub(now))
+			nextReadyAt = nextReadyAtTimer.C()
+		}
+
+		select {
+		case <-q.stopCh:
+			return
+
+		case <-q.heartbeat.C():
+			// continue the loop, which will add ready items
+
+		case <-nextReadyAt:
+			// continue the loop, which will add ready items
+
+		case waitEntry := <-q.waitingForAddCh:
+			if waitEntry.readyAt.After(q.clock.Now()) {
+				insert(waitingForQueue, waitingEntryByData, waitEntry)
+			} else {
+				q.Add(waitEntry.data)
+			}
+
+			drained := false
+			for !drained {
+				select {
+				case waitEntry := <-q.waitingForAddCh:
+					if waitEntry.readyAt.After(q.clock.Now()) {
+						insert(waitingForQueue, waitingEntryByData, waitEntry)
+					} else {
+						q.Add(waitEntry.data)
+					}
+				default:
+					drained = true
+				}
+			}
+		}
+	}
+}
+
+// insert adds the entry to the priority queue, or updates the existing entry
+// if one already exists for the item, keeping the earlier readyAt.
+func insert[T comparable](q *waitForPriorityQueue[T], knownEntries map[T]*waitFor[T], entry *waitFor[T]) {
+	existing, exists := knownEntries[entry.data]
+	if exists {
+		if existing.readyAt.After(entry.readyAt) {
+			existing.readyAt = entry.readyAt
+			heap.Fix(q, existing.index)
+		}
+		return
+	}
+
+	heap.Push(q, entry)
+	knownEntries[entry.data] = entry
+}
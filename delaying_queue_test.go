@@ -0,0 +1,63 @@
+package workqueue
+
+import (
+	"testing"
+	"time"
+
+	testingclock "k8s.io/utils/clock/testing"
+)
+
+// TestDelayingQueueAddAfterWaitsForDelay confirms AddAfter withholds the
+// item until its delay has elapsed, and that it surfaces immediately once
+// the fake clock catches up to readyAt.
+func TestDelayingQueueAddAfterWaitsForDelay(t *testing.T) {
+	c := testingclock.NewFakeClock(time.Now())
+	q := newDelayingQueue[string](c)
+	defer q.ShutDown()
+
+	q.AddAfter("late", time.Minute)
+
+	if n := q.Len(); n != 0 {
+		t.Fatalf("expected item to be withheld before its delay elapses, got Len()=%d", n)
+	}
+
+	// newDelayingQueue's heartbeat ticker is already a waiter on c, so this
+	// does not wait for waitingLoop to register the entry's timer. That is
+	// fine: readyAt is exactly one minute out, so after Step(time.Minute)
+	// the entry is ready whether waitingLoop picks it up before or after
+	// the step, and the heartbeat tick the step fires wakes the loop.
+	for !c.HasWaiters() {
+		time.Sleep(time.Millisecond)
+	}
+	c.Step(time.Minute)
+
+	deadline := time.After(2 * time.Second)
+	for q.Len() == 0 {
+		select {
+		case <-deadline:
+			t.Fatal("item never surfaced after its delay elapsed")
+		default:
+			time.Sleep(time.Millisecond)
+		}
+	}
+
+	item, shutdown := q.Get()
+	if shutdown || item != "late" {
+		t.Errorf("expected (%q, false), got (%q, %v)", "late", item, shutdown)
+	}
+}
+
+// TestDelayingQueueAddAfterNonPositiveIsImmediate confirms a non-positive
+// duration bypasses the waiting loop entirely, per AddAfter's doc comment.
+func TestDelayingQueueAddAfterNonPositiveIsImmediate(t *testing.T) {
+	c := testingclock.NewFakeClock(time.Now())
+	q := newDelayingQueue[string](c)
+	defer q.ShutDown()
+
+	q.AddAfter("now", 0)
+
+	item, shutdown := q.Get()
+	if shutdown || item != "now" {
+		t.Errorf("expected (%q, false), got (%q, %v)", "now", item, shutdown)
+	}
+}
@@ -0,0 +1,20 @@
+package workqueue
+
+import "errors"
+
+var (
+	// ErrFull is returned by AddWithTimeout when the queue is at capacity
+	// and no room opens up before the deadline passes.
+	ErrFull = errors.New("workqueue: queue is full")
+	// ErrTimeout is returned by GetWithTimeout when no item becomes
+	// available before the deadline passes.
+	ErrTimeout = errors.New("workqueue: timed out waiting for an item")
+	// ErrShutDown is returned by AddWithTimeout when the queue has been shut
+	// down, whether before the call or while the caller was waiting.
+	// GetWithTimeout does not return it: like Get, it reports shutdown
+	// through its shutdown result, with a nil error.
+	ErrShutDown = errors.New("workqueue: queue has shut down")
+	// ErrStorageEmpty is returned by a Storage backend's Dequeue when it
+	// holds no items.
+	ErrStorageEmpty = errors.New("workqueue: storage is empty")
+)
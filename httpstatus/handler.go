@@ -0,0 +1,63 @@
+// Package httpstatus exposes a workqueue registry as a JSON status
+// endpoint, e.g. for wiring up /status.json in a service that owns one or
+// more queues.
+package httpstatus
+
+import (
+	"encoding/json"
+	"net/http"
+	"sync"
+
+	"github.com/Kaiser925/workqueue"
+)
+
+// StatusProvider is satisfied by any queue that can report its own status,
+// e.g. any instantiation of workqueue.Interface[T].
+type StatusProvider interface {
+	Status() workqueue.QueueStatus
+}
+
+// Registry holds a set of named queues and serves their combined status as
+// JSON.
+type Registry struct {
+	mu     sync.RWMutex
+	queues map[string]StatusProvider
+}
+
+// NewRegistry constructs an empty Registry.
+func NewRegistry() *Registry {
+	return &Registry{queues: map[string]StatusProvider{}}
+}
+
+// Register adds (or replaces) a named queue in the registry.
+func (r *Registry) Register(name string, queue StatusProvider) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.queues[name] = queue
+}
+
+// Unregister removes a named queue from the registry.
+func (r *Registry) Unregister(name string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	delete(r.queues, name)
+}
+
+// Handler returns an http.Handler that serves the registry's current
+// status as a JSON object keyed by queue name, suitable for mounting at
+// e.g. /status.json.
+func (r *Registry) Handler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		r.mu.RLock()
+		snapshot := make(map[string]workqueue.QueueStatus, len(r.queues))
+		for name, q := range r.queues {
+			snapshot[name] = q.Status()
+		}
+		r.mu.RUnlock()
+
+		w.Header().Set("Content-Type", "application/json")
+		if err := json.NewEncoder(w).Encode(snapshot); err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+		}
+	})
+}
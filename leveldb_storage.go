@@ -0,0 +1,168 @@
+package workqueue
+
+import (
+	"encoding/binary"
+	"sync"
+
+	"github.com/syndtr/goleveldb/leveldb"
+	"github.com/syndtr/goleveldb/leveldb/util"
+)
+
+// levelDBKeyPrefix namespaces queue entries within the database, leaving
+// room for future metadata keys alongside them.
+var levelDBKeyPrefix = []byte("q/")
+
+// LevelDBStorage is a Storage backend that persists items to a LevelDB
+// database, so a FIFO built on it survives process restarts without losing
+// pending work. Items are stored under monotonically increasing keys so
+// Dequeue always returns the oldest surviving entry. goleveldb takes an
+// exclusive lock on the database directory, so only one process can ever
+// have it open at a time; there is no live cross-process writer to watch
+// for, and a restart's pending items are recovered via Replay, not polling.
+type LevelDBStorage[T comparable] struct {
+	db    *leveldb.DB
+	codec Codec[T]
+
+	mu      sync.Mutex
+	nextSeq uint64
+	// count mirrors the number of keys currently in the database, so Len
+	// doesn't have to scan the whole keyspace on every call. It, nextSeq,
+	// and every Put/Delete that changes it are kept under the same lock so
+	// Len can never observe a key scanExisting hasn't accounted for twice.
+	count int
+}
+
+// NewLevelDBStorage opens (or creates) a LevelDB database at path, using
+// codec to marshal items to and from the bytes the database holds.
+func NewLevelDBStorage[T comparable](path string, codec Codec[T]) (*LevelDBStorage[T], error) {
+	db, err := leveldb.OpenFile(path, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	s := &LevelDBStorage[T]{
+		db:    db,
+		codec: codec,
+	}
+
+	seq, count, err := s.scanExisting()
+	if err != nil {
+		db.Close()
+		return nil, err
+	}
+	s.nextSeq = seq + 1
+	s.count = count
+
+	return s, nil
+}
+
+func levelDBKey(seq uint64) []byte {
+	key := make([]byte, len(levelDBKeyPrefix)+8)
+	n := copy(key, levelDBKeyPrefix)
+	binary.BigEndian.PutUint64(key[n:], seq)
+	return key
+}
+
+// scanExisting does the one unavoidable full-keyspace scan, at open time, to
+// recover the highest sequence number written so far and how many keys are
+// currently present. Every subsequent Len() call is O(1) against s.count.
+func (s *LevelDBStorage[T]) scanExisting() (maxSeq uint64, count int, err error) {
+	iter := s.db.NewIterator(util.BytesPrefix(levelDBKeyPrefix), nil)
+	defer iter.Release()
+
+	for iter.Next() {
+		seq := binary.BigEndian.Uint64(iter.Key()[len(levelDBKeyPrefix):])
+		if seq > maxSeq {
+			maxSeq = seq
+		}
+		count++
+	}
+	return maxSeq, count, iter.Error()
+}
+
+func (s *LevelDBStorage[T]) Enqueue(item T) error {
+	data, err := s.codec.Marshal(item)
+	if err != nil {
+		return err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	seq := s.nextSeq
+	if err := s.db.Put(levelDBKey(seq), data, nil); err != nil {
+		return err
+	}
+	s.nextSeq++
+	s.count++
+
+	return nil
+}
+
+func (s *LevelDBStorage[T]) Dequeue() (T, error) {
+	var noop T
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	iter := s.db.NewIterator(util.BytesPrefix(levelDBKeyPrefix), nil)
+	defer iter.Release()
+
+	if !iter.Next() {
+		return noop, ErrStorageEmpty
+	}
+
+	key := append([]byte(nil), iter.Key()...)
+	data := append([]byte(nil), iter.Value()...)
+
+	item, err := s.codec.Unmarshal(data)
+	if err != nil {
+		return noop, err
+	}
+
+	if err := s.db.Delete(key, nil); err != nil {
+		return noop, err
+	}
+	s.count--
+
+	return item, nil
+}
+
+func (s *LevelDBStorage[T]) Len() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.count
+}
+
+func (s *LevelDBStorage[T]) Close() error {
+	return s.db.Close()
+}
+
+// Replay returns every item currently persisted, in key order, so a FIFO
+// reopening this database can rebuild its dirty set after a restart.
+func (s *LevelDBStorage[T]) Replay() ([]T, error) {
+	iter := s.db.NewIterator(util.BytesPrefix(levelDBKeyPrefix), nil)
+	defer iter.Release()
+
+	var items []T
+	for iter.Next() {
+		item, err := s.codec.Unmarshal(iter.Value())
+		if err != nil {
+			return nil, err
+		}
+		items = append(items, item)
+	}
+	return items, iter.Error()
+}
+
+// NewFIFOWithLevelDB constructs a FIFO backed by a LevelDBStorage at path.
+// Any items left over from a previous run are replayed into the dirty set.
+func NewFIFOWithLevelDB[T comparable](path string, opts ...Option[T]) (*FIFO[T], error) {
+	storage, err := NewLevelDBStorage[T](path, JSONCodec[T]{})
+	if err != nil {
+		return nil, err
+	}
+
+	q := New[T](append([]Option[T]{WithStorage[T](storage)}, opts...)...)
+	return q, nil
+}
@@ -0,0 +1,199 @@
+package workqueue
+
+import (
+	"sync/atomic"
+	"time"
+
+	"k8s.io/utils/clock"
+)
+
+// This file provides abstractions for setting the provider (e.g.
+// prometheus) of metrics.
+
+type queueMetrics[T comparable] interface {
+	add(item T)
+	get(item T)
+	done(item T)
+	updateUnfinishedWork()
+}
+
+// GaugeMetric represents a single numerical value that can arbitrarily go
+// up and down.
+type GaugeMetric interface {
+	Inc()
+	Dec()
+}
+
+// SettableGaugeMetric represents a single numerical value that can be set
+// arbitrarily.
+type SettableGaugeMetric interface {
+	Set(float64)
+}
+
+// CounterMetric represents a single numerical value that only ever goes
+// up.
+type CounterMetric interface {
+	Inc()
+}
+
+// HistogramMetric counts individual observations.
+type HistogramMetric interface {
+	Observe(float64)
+}
+
+// noMetrics is the zero-overhead queueMetrics used when no MetricsProvider
+// is configured: every call is a no-op, and newQueue skips starting the
+// unfinished-work goroutine entirely for it.
+type noMetrics[T any] struct{}
+
+func (noMetrics[T]) add(item T)            {}
+func (noMetrics[T]) get(item T)            {}
+func (noMetrics[T]) done(item T)           {}
+func (noMetrics[T]) updateUnfinishedWork() {}
+
+// defaultQueueMetrics expects the caller to lock (via the owning queue's
+// cond) before calling any of its methods.
+type defaultQueueMetrics[T comparable] struct {
+	clock clock.Clock
+
+	// enabled gates addTimes/processingStartTimes, the per-item
+	// bookkeeping behind latency and work-duration observations. Disabling
+	// it removes that overhead while adds/depth keep being counted.
+	enabled atomic.Bool
+
+	// current depth of a workqueue
+	depth GaugeMetric
+	// total number of adds handled by a workqueue
+	adds CounterMetric
+	// how long an item stays in a workqueue before it's first processed
+	latency HistogramMetric
+	// how long processing an item from a workqueue takes
+	workDuration HistogramMetric
+
+	addTimes             map[T]time.Time
+	processingStartTimes map[T]time.Time
+
+	// unfinishedWorkSeconds and longestRunningProcessor are refreshed
+	// periodically by updateUnfinishedWork.
+	unfinishedWorkSeconds   SettableGaugeMetric
+	longestRunningProcessor SettableGaugeMetric
+}
+
+func (m *defaultQueueMetrics[T]) add(item T) {
+	if m == nil {
+		return
+	}
+
+	m.adds.Inc()
+	m.depth.Inc()
+
+	if !m.enabled.Load() {
+		return
+	}
+	if _, exists := m.addTimes[item]; !exists {
+		m.addTimes[item] = m.clock.Now()
+	}
+}
+
+func (m *defaultQueueMetrics[T]) get(item T) {
+	if m == nil {
+		return
+	}
+
+	m.depth.Dec()
+
+	if !m.enabled.Load() {
+		return
+	}
+	m.processingStartTimes[item] = m.clock.Now()
+	if startTime, exists := m.addTimes[item]; exists {
+		m.latency.Observe(m.sinceInSeconds(startTime))
+		delete(m.addTimes, item)
+	}
+}
+
+func (m *defaultQueueMetrics[T]) done(item T) {
+	if m == nil {
+		return
+	}
+
+	if !m.enabled.Load() {
+		return
+	}
+	if startTime, exists := m.processingStartTimes[item]; exists {
+		m.workDuration.Observe(m.sinceInSeconds(startTime))
+		delete(m.processingStartTimes, item)
+	}
+}
+
+func (m *defaultQueueMetrics[T]) updateUnfinishedWork() {
+	// Note that a summary metric would be better for this, but the
+	// Prometheus client for Go doesn't support sorting, which we'd need.
+	total := float64(0)
+	var oldest float64
+	for _, t := range m.processingStartTimes {
+		age := m.sinceInSeconds(t)
+		total += age
+		if age > oldest {
+			oldest = age
+		}
+	}
+	m.unfinishedWorkSeconds.Set(total)
+	m.longestRunningProcessor.Set(oldest)
+}
+
+func (m *defaultQueueMetrics[T]) setEnabled(enabled bool) {
+	if m == nil {
+		return
+	}
+
+	m.enabled.Store(enabled)
+	if !enabled {
+		// Drop whatever bookkeeping has accumulated rather than let it
+		// grow unbounded while disabled.
+		m.addTimes = map[T]time.Time{}
+		m.processingStartTimes = map[T]time.Time{}
+	}
+}
+
+func (m *defaultQueueMetrics[T]) sinceInSeconds(start time.Time) float64 {
+	return m.clock.Since(start).Seconds()
+}
+
+// MetricsProvider generates various metrics used by the queue.
+type MetricsProvider interface {
+	NewDepthMetric(name string) GaugeMetric
+	NewAddsMetric(name string) CounterMetric
+	NewLatencyMetric(name string) HistogramMetric
+	NewWorkDurationMetric(name string) HistogramMetric
+	NewUnfinishedWorkSecondsMetric(name string) SettableGaugeMetric
+	NewLongestRunningProcessorSecondsMetric(name string) SettableGaugeMetric
+	NewRetriesMetric(name string) CounterMetric
+}
+
+// queueMetricsFactory builds a queueMetrics for a named queue from a
+// MetricsProvider, falling back to noMetrics when either is unset.
+type queueMetricsFactory[T comparable] struct {
+	metricsProvider MetricsProvider
+}
+
+func (f queueMetricsFactory[T]) newQueueMetrics(name string, c clock.Clock) queueMetrics[T] {
+	if len(name) == 0 || f.metricsProvider == nil {
+		return noMetrics[T]{}
+	}
+
+	m := &defaultQueueMetrics[T]{
+		clock:                   c,
+		depth:                   f.metricsProvider.NewDepthMetric(name),
+		adds:                    f.metricsProvider.NewAddsMetric(name),
+		latency:                 f.metricsProvider.NewLatencyMetric(name),
+		workDuration:            f.metricsProvider.NewWorkDurationMetric(name),
+		addTimes:                map[T]time.Time{},
+		processingStartTimes:    map[T]time.Time{},
+		unfinishedWorkSeconds:   f.metricsProvider.NewUnfinishedWorkSecondsMetric(name),
+		longestRunningProcessor: f.metricsProvider.NewLongestRunningProcessorSecondsMetric(name),
+	}
+	m.enabled.Store(true)
+
+	return m
+}
@@ -260,3 +260,35 @@ func TestMetrics(t *testing.T) {
 		t.Errorf("expected %v, got %v", e, a)
 	}
 }
+
+func benchmarkAddGetDone(b *testing.B, q *FIFO[int]) {
+	defer q.ShutDown()
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		q.Add(i)
+		item, shutdown := q.Get()
+		if shutdown {
+			b.Fatal("queue shut down unexpectedly")
+		}
+		q.Done(item)
+	}
+}
+
+func BenchmarkAddGetDone_NoMetrics(b *testing.B) {
+	benchmarkAddGetDone(b, New[int]())
+}
+
+func BenchmarkAddGetDone_WithMetrics(b *testing.B) {
+	benchmarkAddGetDone(b, New[int](WithMetrics[int](&testMetricsProvider{}, true)))
+}
+
+// BenchmarkAddGetDone_WithMetricsDisabled isolates the cost of the
+// defaultQueueMetrics.enabled check itself: unlike _NoMetrics, this queue
+// still has a queueMetrics implementation wired in (counters/gauges still
+// fire), but the per-item addTimes/processingStartTimes bookkeeping behind
+// latency and work-duration observations is switched off. The delta between
+// this and _WithMetrics is what SetMetricsEnabled(false) buys you.
+func BenchmarkAddGetDone_WithMetricsDisabled(b *testing.B) {
+	benchmarkAddGetDone(b, New[int](WithMetrics[int](&testMetricsProvider{}, false)))
+}
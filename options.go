@@ -0,0 +1,81 @@
+package workqueue
+
+import (
+	"time"
+
+	"k8s.io/utils/clock"
+)
+
+// Option configures a FIFO at construction time.
+type Option[T comparable] func(*FIFO[T])
+
+// WithCapacity bounds the number of items a FIFO will hold in flight
+// (queued plus currently being processed). Once at capacity, Add blocks
+// until an in-progress item completes via Done; AddWithTimeout bounds that
+// wait. A capacity of 0, the default, means unbounded.
+func WithCapacity[T comparable](n int) Option[T] {
+	return func(q *FIFO[T]) {
+		q.capacity = n
+	}
+}
+
+// WithStorage replaces the FIFO's default in-memory MemoryStorage with the
+// given backend, e.g. a LevelDBStorage for crash-safe queuing. If storage
+// already holds items from a previous process, and it implements Replayer,
+// those items are replayed into the dirty set so they aren't re-processed
+// as duplicates of whatever Add calls follow.
+func WithStorage[T comparable](storage Storage[T]) Option[T] {
+	return func(q *FIFO[T]) {
+		q.storage = storage
+
+		if r, ok := storage.(Replayer[T]); ok {
+			if items, err := r.Replay(); err == nil {
+				for _, item := range items {
+					q.dirty.insert(item)
+				}
+			}
+		}
+	}
+}
+
+// WithMetrics opts a FIFO into metrics collection via provider. enabled
+// controls the initial state of the per-item timestamp bookkeeping behind
+// latency and work-duration observations, and whether the unfinished-work
+// update loop starts immediately; toggle it later with SetMetricsEnabled,
+// which starts the loop lazily if it hadn't already. Passing a nil provider
+// is equivalent to not using this option at all.
+func WithMetrics[T comparable](provider MetricsProvider, enabled bool) Option[T] {
+	return func(q *FIFO[T]) {
+		if provider == nil {
+			return
+		}
+
+		m := &defaultQueueMetrics[T]{
+			clock:                   q.clock,
+			depth:                   provider.NewDepthMetric(""),
+			adds:                    provider.NewAddsMetric(""),
+			latency:                 provider.NewLatencyMetric(""),
+			workDuration:            provider.NewWorkDurationMetric(""),
+			addTimes:                map[T]time.Time{},
+			processingStartTimes:    map[T]time.Time{},
+			unfinishedWorkSeconds:   provider.NewUnfinishedWorkSecondsMetric(""),
+			longestRunningProcessor: provider.NewLongestRunningProcessorSecondsMetric(""),
+		}
+		m.enabled.Store(enabled)
+
+		q.metrics = m
+		if enabled {
+			q.metricsLoopStarted.Do(func() { go q.updateUnfinishedWorkLoop() })
+		}
+	}
+}
+
+// New constructs a ready-to-use FIFO using the real clock and no metrics
+// collection, configured by the given options.
+func New[T comparable](opts ...Option[T]) *FIFO[T] {
+	q := newQueue[T](clock.RealClock{}, noMetrics[T]{}, defaultUnfinishedWorkUpdatePeriod)
+	for _, opt := range opts {
+		opt(q)
+	}
+	return q
+}
@@ -0,0 +1,424 @@
+package workqueue
+
+import (
+	"container/heap"
+	"fmt"
+	"sync"
+	"time"
+
+	"k8s.io/utils/clock"
+)
+
+// AgingPolicy periodically bumps the priority of items that have been
+// sitting in a PriorityFIFO, so a steady stream of high-priority additions
+// can't starve out older, lower-priority work.
+type AgingPolicy struct {
+	// Every controls how often queued items are re-evaluated for aging.
+	Every time.Duration
+	// Bump is how much priority to add to every still-queued item each
+	// time aging runs.
+	Bump int
+}
+
+type priorityEntry[T comparable] struct {
+	item     T
+	priority int
+	seq      uint64
+	index    int
+}
+
+// priorityHeap orders entries by priority (highest first), breaking ties by
+// seq so equal priorities stay FIFO.
+type priorityHeap[T comparable] []*priorityEntry[T]
+
+func (h priorityHeap[T]) Len() int { return len(h) }
+
+func (h priorityHeap[T]) Less(i, j int) bool {
+	if h[i].priority != h[j].priority {
+		return h[i].priority > h[j].priority
+	}
+	return h[i].seq < h[j].seq
+}
+
+func (h priorityHeap[T]) Swap(i, j int) {
+	h[i], h[j] = h[j], h[i]
+	h[i].index = i
+	h[j].index = j
+}
+
+func (h *priorityHeap[T]) Push(x interface{}) {
+	e := x.(*priorityEntry[T])
+	e.index = len(*h)
+	*h = append(*h, e)
+}
+
+func (h *priorityHeap[T]) Pop() interface{} {
+	old := *h
+	n := len(old)
+	e := old[n-1]
+	old[n-1] = nil
+	e.index = -1
+	*h = old[:n-1]
+	return e
+}
+
+// PriorityFIFO is a work queue that orders items by an integer priority,
+// falling back to FIFO order among items of equal priority. It satisfies
+// Interface[T]; use AddWithPriority instead of Add to expedite an item.
+type PriorityFIFO[T comparable] struct {
+	heap    priorityHeap[T]
+	entries map[T]*priorityEntry[T]
+
+	// pending holds the priority requested for an item that is currently
+	// processing, so that Done can re-queue it at the right priority.
+	pending map[T]int
+
+	dirty      set[T]
+	processing set[T]
+
+	cond *sync.Cond
+
+	shuttingDown bool
+	drain        bool
+
+	nextSeq uint64
+
+	clock clock.WithTicker
+
+	aging     AgingPolicy
+	stopAging chan struct{}
+
+	metricsProvider MetricsProvider
+	metricsPrefix   string
+	depthGauges     map[int]GaugeMetric
+
+	// enqueuedAt and processingAt back Status()'s age calculations.
+	enqueuedAt   map[T]time.Time
+	processingAt map[T]time.Time
+}
+
+var _ Interface[string] = &PriorityFIFO[string]{}
+
+// PriorityOption configures a PriorityFIFO at construction time.
+type PriorityOption[T comparable] func(*PriorityFIFO[T])
+
+// WithAgingPolicy enables starvation avoidance: every policy.Every, every
+// item still waiting in the queue has its priority bumped by policy.Bump.
+func WithAgingPolicy[T comparable](policy AgingPolicy) PriorityOption[T] {
+	return func(q *PriorityFIFO[T]) {
+		q.aging = policy
+	}
+}
+
+// WithPriorityMetrics registers depth gauges with provider, named
+// "<prefix>_priority_<n>". Gauges are per bucket of priorityGaugeBucketSize
+// priority levels, not per level: n is the lowest priority in the bucket,
+// and priorities beyond priorityGaugeMaxBucket buckets either side of zero
+// share the outermost gauge. A gauge is created the first time its bucket
+// sees traffic.
+func WithPriorityMetrics[T comparable](provider MetricsProvider, prefix string) PriorityOption[T] {
+	return func(q *PriorityFIFO[T]) {
+		q.metricsProvider = provider
+		q.metricsPrefix = prefix
+	}
+}
+
+// NewPriorityFIFO constructs a PriorityFIFO, applying any given options.
+func NewPriorityFIFO[T comparable](opts ...PriorityOption[T]) *PriorityFIFO[T] {
+	q := &PriorityFIFO[T]{
+		entries:      map[T]*priorityEntry[T]{},
+		pending:      map[T]int{},
+		dirty:        set[T]{},
+		processing:   set[T]{},
+		cond:         sync.NewCond(&sync.Mutex{}),
+		clock:        clock.RealClock{},
+		depthGauges:  map[int]GaugeMetric{},
+		enqueuedAt:   map[T]time.Time{},
+		processingAt: map[T]time.Time{},
+	}
+
+	for _, opt := range opts {
+		opt(q)
+	}
+
+	if q.aging.Every > 0 {
+		q.stopAging = make(chan struct{})
+		go q.agingLoop()
+	}
+
+	return q
+}
+
+// Add marks item as needing processing at the default priority (0).
+func (q *PriorityFIFO[T]) Add(item T) {
+	q.AddWithPriority(item, 0)
+}
+
+// AddWithPriority marks item as needing processing at the given priority.
+// If item is already queued or processing, its priority is upgraded to
+// max(existing, priority) rather than being lowered.
+func (q *PriorityFIFO[T]) AddWithPriority(item T, priority int) {
+	q.cond.L.Lock()
+	defer q.cond.L.Unlock()
+
+	if q.shuttingDown {
+		return
+	}
+
+	if q.dirty.has(item) {
+		if entry, ok := q.entries[item]; ok {
+			if priority > entry.priority {
+				q.moveGauge(entry.priority, priority)
+				entry.priority = priority
+				heap.Fix(&q.heap, entry.index)
+			}
+			return
+		}
+
+		// It's currently processing; remember the highest requested
+		// priority so Done can re-queue it correctly.
+		if existing, ok := q.pending[item]; !ok || priority > existing {
+			q.pending[item] = priority
+		}
+		return
+	}
+
+	q.dirty.insert(item)
+	if q.processing.has(item) {
+		q.pending[item] = priority
+		return
+	}
+
+	q.pushLocked(item, priority)
+	q.cond.Signal()
+}
+
+func (q *PriorityFIFO[T]) pushLocked(item T, priority int) {
+	entry := &priorityEntry[T]{item: item, priority: priority, seq: q.nextSeq}
+	q.nextSeq++
+	heap.Push(&q.heap, entry)
+	q.entries[item] = entry
+	q.enqueuedAt[item] = q.clock.Now()
+	q.incGauge(priority)
+}
+
+// Len returns the current queue length, for informational purposes only.
+func (q *PriorityFIFO[T]) Len() int {
+	q.cond.L.Lock()
+	defer q.cond.L.Unlock()
+	return q.heap.Len()
+}
+
+// Get blocks until it can return the highest-priority item to be processed.
+// If shutdown = true, the caller should end their goroutine.
+func (q *PriorityFIFO[T]) Get() (item T, shutdown bool) {
+	q.cond.L.Lock()
+	defer q.cond.L.Unlock()
+	for q.heap.Len() == 0 && !q.shuttingDown {
+		q.cond.Wait()
+	}
+	if q.heap.Len() == 0 {
+		var noop T
+		return noop, true
+	}
+
+	entry := heap.Pop(&q.heap).(*priorityEntry[T])
+	delete(q.entries, entry.item)
+	q.decGauge(entry.priority)
+
+	delete(q.enqueuedAt, entry.item)
+	q.processingAt[entry.item] = q.clock.Now()
+
+	q.processing.insert(entry.item)
+	q.dirty.delete(entry.item)
+
+	return entry.item, false
+}
+
+// Done marks item as done processing, re-queuing it at its pending priority
+// if it was marked dirty again while being processed.
+func (q *PriorityFIFO[T]) Done(item T) {
+	q.cond.L.Lock()
+	defer q.cond.L.Unlock()
+
+	q.processing.delete(item)
+	delete(q.processingAt, item)
+	if q.dirty.has(item) {
+		priority := q.pending[item]
+		delete(q.pending, item)
+		q.pushLocked(item, priority)
+		q.cond.Signal()
+	} else if q.processing.len() == 0 {
+		q.cond.Signal()
+	}
+}
+
+// Status returns a snapshot of the queue's current state. It is safe to
+// call concurrently with any other method.
+func (q *PriorityFIFO[T]) Status() QueueStatus {
+	q.cond.L.Lock()
+
+	status := QueueStatus{
+		Queued:       q.heap.Len(),
+		InProgress:   q.processing.len(),
+		Dirty:        q.dirty.len(),
+		ShuttingDown: q.shuttingDown,
+	}
+
+	oldestEnqueue := oldestTime(q.enqueuedAt)
+	oldestProcessing := oldestTime(q.processingAt)
+	now := q.clock.Now()
+
+	q.cond.L.Unlock()
+
+	if !oldestEnqueue.IsZero() {
+		status.OldestEnqueueAge = now.Sub(oldestEnqueue)
+	}
+	if !oldestProcessing.IsZero() {
+		status.LongestProcessingAge = now.Sub(oldestProcessing)
+	}
+
+	return status
+}
+
+// ShutDown will cause q to ignore all new items added to it and immediately
+// instruct the worker goroutines to exit.
+func (q *PriorityFIFO[T]) ShutDown() {
+	q.setDrain(false)
+	q.shutdown()
+}
+
+// ShutDownWithDrain will shut down the work queue and drain the queue.
+func (q *PriorityFIFO[T]) ShutDownWithDrain() {
+	q.setDrain(true)
+	q.shutdown()
+	for q.shouldDrain() && q.processingLenLocked() > 0 {
+		q.clock.Sleep(10 * time.Millisecond)
+	}
+}
+
+func (q *PriorityFIFO[T]) processingLenLocked() int {
+	q.cond.L.Lock()
+	defer q.cond.L.Unlock()
+	return q.processing.len()
+}
+
+func (q *PriorityFIFO[T]) setDrain(shouldDrain bool) {
+	q.cond.L.Lock()
+	defer q.cond.L.Unlock()
+	q.drain = shouldDrain
+}
+
+func (q *PriorityFIFO[T]) shouldDrain() bool {
+	q.cond.L.Lock()
+	defer q.cond.L.Unlock()
+	return q.drain
+}
+
+func (q *PriorityFIFO[T]) shutdown() {
+	q.cond.L.Lock()
+	defer q.cond.L.Unlock()
+	q.shuttingDown = true
+	q.cond.Broadcast()
+
+	if q.stopAging != nil {
+		select {
+		case <-q.stopAging:
+		default:
+			close(q.stopAging)
+		}
+	}
+}
+
+// ShuttingDown returns true if the work queue is shutting down.
+func (q *PriorityFIFO[T]) ShuttingDown() bool {
+	q.cond.L.Lock()
+	defer q.cond.L.Unlock()
+	return q.shuttingDown
+}
+
+// agingLoop bumps the priority of every still-queued item by q.aging.Bump
+// every q.aging.Every, so old low-priority work isn't starved out.
+func (q *PriorityFIFO[T]) agingLoop() {
+	t := q.clock.NewTicker(q.aging.Every)
+	defer t.Stop()
+
+	for {
+		select {
+		case <-q.stopAging:
+			return
+		case <-t.C():
+			q.ageLocked()
+		}
+	}
+}
+
+func (q *PriorityFIFO[T]) ageLocked() {
+	q.cond.L.Lock()
+	defer q.cond.L.Unlock()
+
+	for _, entry := range q.heap {
+		q.moveGauge(entry.priority, entry.priority+q.aging.Bump)
+		entry.priority += q.aging.Bump
+	}
+	heap.Init(&q.heap)
+}
+
+// priorityGaugeBucketSize and priorityGaugeMaxBucket bound how many depth
+// gauges gaugeFor can ever create. Without a ceiling, an AgingPolicy that
+// runs for a long time would bump priorities forever and gaugeFor would
+// manufacture a new gauge for every distinct value it had never seen.
+const (
+	priorityGaugeBucketSize = 16
+	priorityGaugeMaxBucket  = 64
+)
+
+// bucketPriority maps a priority onto a fixed-size bucket, so the number of
+// distinct depth gauges stays bounded no matter how far aging bumps
+// priorities.
+func bucketPriority(priority int) int {
+	b := priority / priorityGaugeBucketSize
+	if priority < 0 && priority%priorityGaugeBucketSize != 0 {
+		b--
+	}
+	switch {
+	case b > priorityGaugeMaxBucket:
+		b = priorityGaugeMaxBucket
+	case b < -priorityGaugeMaxBucket:
+		b = -priorityGaugeMaxBucket
+	}
+	return b * priorityGaugeBucketSize
+}
+
+func (q *PriorityFIFO[T]) gaugeFor(priority int) GaugeMetric {
+	if q.metricsProvider == nil {
+		return nil
+	}
+	bucket := bucketPriority(priority)
+	g, ok := q.depthGauges[bucket]
+	if !ok {
+		g = q.metricsProvider.NewDepthMetric(fmt.Sprintf("%s_priority_%d", q.metricsPrefix, bucket))
+		q.depthGauges[bucket] = g
+	}
+	return g
+}
+
+func (q *PriorityFIFO[T]) incGauge(priority int) {
+	if g := q.gaugeFor(priority); g != nil {
+		g.Inc()
+	}
+}
+
+func (q *PriorityFIFO[T]) decGauge(priority int) {
+	if g := q.gaugeFor(priority); g != nil {
+		g.Dec()
+	}
+}
+
+func (q *PriorityFIFO[T]) moveGauge(from, to int) {
+	if from == to {
+		return
+	}
+	q.decGauge(from)
+	q.incGauge(to)
+}
@@ -0,0 +1,72 @@
+package workqueue
+
+import (
+	"testing"
+	"time"
+)
+
+// TestPriorityFIFOOrdersByPriorityThenFIFO confirms items come back
+// highest-priority first, and that equal priorities fall back to the order
+// they were added in.
+func TestPriorityFIFOOrdersByPriorityThenFIFO(t *testing.T) {
+	q := NewPriorityFIFO[string]()
+	defer q.ShutDown()
+
+	q.AddWithPriority("low", 0)
+	q.AddWithPriority("high-1", 10)
+	q.AddWithPriority("mid", 5)
+	q.AddWithPriority("high-2", 10)
+
+	want := []string{"high-1", "high-2", "mid", "low"}
+	for _, w := range want {
+		item, shutdown := q.Get()
+		if shutdown || item != w {
+			t.Fatalf("expected %q, got %q (shutdown=%v)", w, item, shutdown)
+		}
+	}
+}
+
+// TestPriorityFIFOAgingPreventsStarvation confirms that an AgingPolicy
+// eventually surfaces an old, low-priority item even in the face of a
+// steady stream of higher-priority additions that would otherwise starve
+// it out forever.
+func TestPriorityFIFOAgingPreventsStarvation(t *testing.T) {
+	q := NewPriorityFIFO[int](WithAgingPolicy[int](AgingPolicy{
+		Every: 5 * time.Millisecond,
+		Bump:  1000,
+	}))
+	defer q.ShutDown()
+
+	q.AddWithPriority(-1, 0) // the item that must not starve
+
+	stop := make(chan struct{})
+	defer close(stop)
+	go func() {
+		for i := 0; ; i++ {
+			select {
+			case <-stop:
+				return
+			default:
+				q.AddWithPriority(i, 10)
+				time.Sleep(time.Millisecond)
+			}
+		}
+	}()
+
+	deadline := time.After(2 * time.Second)
+	for {
+		item, shutdown := q.Get()
+		if shutdown {
+			t.Fatal("queue shut down unexpectedly")
+		}
+		if item == -1 {
+			return
+		}
+		q.Done(item)
+		select {
+		case <-deadline:
+			t.Fatal("item -1 was starved out despite the aging policy")
+		default:
+		}
+	}
+}
@@ -13,7 +13,7 @@ type Interface[T comparable] interface {
 	// Len returns the number of items in the work queue.
 	Len() int
 	// Get returns the next item in the work queue and marks it as done.
-	Get() (T, shutdown bool)
+	Get() (item T, shutdown bool)
 	// Done marks the item as done.
 	Done(item T)
 	// ShutDown shuts down the work queue.
@@ -22,14 +22,19 @@ type Interface[T comparable] interface {
 	ShutDownWithDrain()
 	// ShuttingDown returns true if the work queue is shutting down.
 	ShuttingDown() bool
+	// Status returns a snapshot of the queue's current state, for
+	// introspection (e.g. a status endpoint).
+	Status() QueueStatus
 }
 
 // FIFO is a basic FIFO work queue.
 type FIFO[T comparable] struct {
-	// queue defines the order in which we will work on items. Every
-	// element of queue should be in the dirty set and not in the
-	// processing set.
-	queue []*T
+	// storage defines the order in which we will work on items. Every
+	// item held in storage should be in the dirty set and not in the
+	// processing set. By default this is an in-memory MemoryStorage, which
+	// holds items directly, but it can be swapped for a durable backend via
+	// WithStorage.
+	storage Storage[T]
 
 	// dirty defines all of the items that need to be processed.
 	dirty set[T]
@@ -46,9 +51,31 @@ type FIFO[T comparable] struct {
 	drain        bool
 
 	metrics queueMetrics[T]
+	// metricsLoopStarted guards updateUnfinishedWorkLoop's goroutine so it
+	// is started at most once, whether that happens at construction time
+	// (newQueue) or lazily, when WithMetrics(provider, false) is later
+	// flipped on via SetMetricsEnabled.
+	metricsLoopStarted sync.Once
 
 	unfinishedWorkUpdatePeriod time.Duration
 	clock                      clock.WithTicker
+
+	// capacity bounds the number of items the queue will hold in flight
+	// (queued plus currently being processed). Zero means unbounded.
+	capacity int
+
+	// addWaiters holds producers parked in Add/AddWithTimeout while the
+	// queue is at capacity, in FIFO order.
+	addWaiters []*waiter
+	// getWaiters holds consumers parked in GetWithTimeout while the queue
+	// is empty, in FIFO order.
+	getWaiters []*waiter
+
+	// enqueuedAt and processingAt back Status()'s age calculations. They
+	// are tracked independently of metrics so introspection still works
+	// with metrics disabled.
+	enqueuedAt   map[T]time.Time
+	processingAt map[T]time.Time
 }
 
 type empty struct{}
@@ -72,10 +99,26 @@ func (s set[T]) len() int {
 	return len(s)
 }
 
-// Add marks item as needing processing.
+// Add marks item as needing processing. If the queue was constructed with
+// WithCapacity and is currently at capacity, Add blocks until an in-progress
+// item completes via Done or the queue is shut down. Use AddWithTimeout to
+// bound how long Add may block.
 func (q *FIFO[T]) Add(item T) {
 	q.cond.L.Lock()
 	defer q.cond.L.Unlock()
+
+	for !q.dirty.has(item) && q.isFullLocked() && !q.shuttingDown {
+		w := q.newAddWaiterLocked()
+		q.cond.L.Unlock()
+		<-w.ch
+		q.cond.L.Lock()
+	}
+
+	q.addLocked(item)
+}
+
+// addLocked performs the actual enqueue. The caller must hold q.cond.L.
+func (q *FIFO[T]) addLocked(item T) {
 	if q.shuttingDown {
 		return
 	}
@@ -90,8 +133,16 @@ func (q *FIFO[T]) Add(item T) {
 		return
 	}
 
-	q.queue = append(q.queue, &item)
+	if err := q.storage.Enqueue(item); err != nil {
+		// Nothing we can do with an item storage won't take; drop it
+		// rather than wedging the queue.
+		q.dirty.delete(item)
+		return
+	}
+
+	q.enqueuedAt[item] = q.clock.Now()
 	q.cond.Signal()
+	q.wakeNextGetWaiterLocked()
 }
 
 // Len returns the current queue length, for informational purposes only. You
@@ -100,7 +151,7 @@ func (q *FIFO[T]) Add(item T) {
 func (q *FIFO[T]) Len() int {
 	q.cond.L.Lock()
 	defer q.cond.L.Unlock()
-	return len(q.queue)
+	return q.storage.Len()
 }
 
 // Get blocks until it can return an item to be processed. If shutdown = true,
@@ -109,22 +160,37 @@ func (q *FIFO[T]) Len() int {
 func (q *FIFO[T]) Get() (item T, shutdown bool) {
 	q.cond.L.Lock()
 	defer q.cond.L.Unlock()
-	for len(q.queue) == 0 && !q.shuttingDown {
+	for q.storage.Len() == 0 && !q.shuttingDown {
 		q.cond.Wait()
 	}
-	if len(q.queue) == 0 {
+
+	return q.getLocked()
+}
+
+// getLocked performs the actual dequeue. The caller must hold q.cond.L and
+// must have already established that the queue is non-empty or shutting
+// down.
+func (q *FIFO[T]) getLocked() (item T, shutdown bool) {
+	if q.storage.Len() == 0 {
 		// We must be shutting down.
 		var noop T
 		return noop, true
 	}
 
-	item = *q.queue[0]
-	// The underlying array still exists and reference this object, so the object will not be garbage collected.
-	q.queue[0] = nil
-	q.queue = q.queue[1:]
+	item, err := q.storage.Dequeue()
+	if err != nil {
+		// The backend disagrees with its own Len(), or failed to decode
+		// what it had stored; treat it the same as empty rather than
+		// returning a zero value to a caller expecting real work.
+		var noop T
+		return noop, true
+	}
 
 	q.metrics.get(item)
 
+	delete(q.enqueuedAt, item)
+	q.processingAt[item] = q.clock.Now()
+
 	q.processing.insert(item)
 	q.dirty.delete(item)
 
@@ -141,12 +207,39 @@ func (q *FIFO[T]) Done(item T) {
 	q.metrics.done(item)
 
 	q.processing.delete(item)
+	delete(q.processingAt, item)
 	if q.dirty.has(item) {
-		q.queue = append(q.queue, &item)
-		q.cond.Signal()
+		if err := q.storage.Enqueue(item); err == nil {
+			q.enqueuedAt[item] = q.clock.Now()
+			q.cond.Signal()
+			q.wakeNextGetWaiterLocked()
+		}
 	} else if q.processing.len() == 0 {
 		q.cond.Signal()
 	}
+
+	q.wakeNextAddWaiterLocked()
+}
+
+// SetMetricsEnabled toggles, at runtime, whether this queue's metrics track
+// the per-item timestamps backing latency and work-duration observations.
+// Disabling it removes that bookkeeping's overhead under high churn while
+// adds/depth keep being counted; it is a no-op unless the queue was built
+// with WithMetrics.
+func (q *FIFO[T]) SetMetricsEnabled(enabled bool) {
+	q.cond.L.Lock()
+	m, ok := q.metrics.(*defaultQueueMetrics[T])
+	if ok {
+		m.setEnabled(enabled)
+	}
+	q.cond.L.Unlock()
+
+	// WithMetrics(provider, false) deliberately leaves
+	// updateUnfinishedWorkLoop unstarted, so start it now that metrics are
+	// live; metricsLoopStarted ensures this only ever happens once.
+	if ok && enabled {
+		q.metricsLoopStarted.Do(func() { go q.updateUnfinishedWorkLoop() })
+	}
 }
 
 // ShutDown will cause q to ignore all new items added to it and
@@ -156,6 +249,29 @@ func (q *FIFO[T]) ShutDown() {
 	q.shutdown()
 }
 
+// ShutDownWithDrain will shut down the work queue and wait until all items
+// that are currently being processed finish via Done before returning.
+func (q *FIFO[T]) ShutDownWithDrain() {
+	q.setDrain(true)
+	q.shutdown()
+	for q.shouldDrain() && q.processingLenLocked() > 0 {
+		q.clock.Sleep(10 * time.Millisecond)
+	}
+}
+
+// ShuttingDown returns true if the work queue is shutting down.
+func (q *FIFO[T]) ShuttingDown() bool {
+	q.cond.L.Lock()
+	defer q.cond.L.Unlock()
+	return q.shuttingDown
+}
+
+func (q *FIFO[T]) processingLenLocked() int {
+	q.cond.L.Lock()
+	defer q.cond.L.Unlock()
+	return q.processing.len()
+}
+
 func (q *FIFO[T]) setDrain(shouldDrain bool) {
 	q.cond.L.Lock()
 	defer q.cond.L.Unlock()
@@ -170,9 +286,23 @@ func (q *FIFO[T]) shouldDrain() bool {
 
 func (q *FIFO[T]) shutdown() {
 	q.cond.L.Lock()
-	defer q.cond.L.Unlock()
 	q.shuttingDown = true
 	q.cond.Broadcast()
+
+	for _, w := range q.addWaiters {
+		close(w.ch)
+	}
+	q.addWaiters = nil
+	for _, w := range q.getWaiters {
+		close(w.ch)
+	}
+	q.getWaiters = nil
+	q.cond.L.Unlock()
+
+	// Release whatever the backend is holding (e.g. a LevelDBStorage's
+	// file handle and lock) now that nothing will call into it again.
+	// Done outside the lock since Close may do its own I/O.
+	_ = q.storage.Close()
 }
 
 func (q *FIFO[T]) updateUnfinishedWorkLoop() {
@@ -197,17 +327,20 @@ func (q *FIFO[T]) updateUnfinishedWorkLoop() {
 func newQueue[T comparable](c clock.WithTicker, metrics queueMetrics[T], updatePeriod time.Duration) *FIFO[T] {
 	t := &FIFO[T]{
 		clock:                      c,
+		storage:                    NewMemoryStorage[T](),
 		dirty:                      set[T]{},
 		processing:                 set[T]{},
 		cond:                       sync.NewCond(&sync.Mutex{}),
 		metrics:                    metrics,
 		unfinishedWorkUpdatePeriod: updatePeriod,
+		enqueuedAt:                 map[T]time.Time{},
+		processingAt:               map[T]time.Time{},
 	}
 
 	// Don't start the goroutine for a type of noMetrics so we don't consume
 	// resources unnecessarily
 	if _, ok := metrics.(noMetrics[T]); !ok {
-		go t.updateUnfinishedWorkLoop()
+		t.metricsLoopStarted.Do(func() { go t.updateUnfinishedWorkLoop() })
 	}
 
 	return t
@@ -0,0 +1,64 @@
+package workqueue
+
+import "time"
+
+// QueueStatus is a point-in-time snapshot of a queue's state, suitable for
+// serializing onto a status endpoint.
+type QueueStatus struct {
+	// Queued is the number of items waiting to be processed.
+	Queued int `json:"queued"`
+	// InProgress is the number of items currently being processed.
+	InProgress int `json:"inProgress"`
+	// Dirty is the number of items marked as needing processing, whether
+	// queued or in progress.
+	Dirty int `json:"dirty"`
+	// OldestEnqueueAge is how long the oldest currently-queued item has
+	// been waiting, or zero if nothing is queued.
+	OldestEnqueueAge time.Duration `json:"oldestEnqueueAge"`
+	// LongestProcessingAge is how long the longest-running item has been
+	// in progress, or zero if nothing is being processed.
+	LongestProcessingAge time.Duration `json:"longestProcessingAge"`
+	// ShuttingDown is true once ShutDown or ShutDownWithDrain has been
+	// called.
+	ShuttingDown bool `json:"shuttingDown"`
+}
+
+// Status returns a snapshot of the queue's current state. It is safe to
+// call concurrently with any other method.
+func (q *FIFO[T]) Status() QueueStatus {
+	q.cond.L.Lock()
+
+	status := QueueStatus{
+		Queued:       q.storage.Len(),
+		InProgress:   q.processing.len(),
+		Dirty:        q.dirty.len(),
+		ShuttingDown: q.shuttingDown,
+	}
+
+	oldestEnqueue := oldestTime(q.enqueuedAt)
+	oldestProcessing := oldestTime(q.processingAt)
+	now := q.clock.Now()
+
+	q.cond.L.Unlock()
+
+	if !oldestEnqueue.IsZero() {
+		status.OldestEnqueueAge = now.Sub(oldestEnqueue)
+	}
+	if !oldestProcessing.IsZero() {
+		status.LongestProcessingAge = now.Sub(oldestProcessing)
+	}
+
+	return status
+}
+
+// oldestTime returns the earliest timestamp in times, or the zero time if
+// times is empty.
+func oldestTime[T comparable](times map[T]time.Time) time.Time {
+	var oldest time.Time
+	for _, t := range times {
+		if oldest.IsZero() || t.Before(oldest) {
+			oldest = t
+		}
+	}
+	return oldest
+}
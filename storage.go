@@ -0,0 +1,96 @@
+package workqueue
+
+import (
+	"encoding/json"
+	"sync"
+)
+
+// Storage is the backing store behind a FIFO's queue ordering. The default
+// is an in-memory MemoryStorage, which holds items directly; swap it with
+// WithStorage for a durable backend such as LevelDBStorage, which marshals
+// items to bytes internally via a Codec.
+type Storage[T comparable] interface {
+	// Enqueue appends item to the back of the backing store.
+	Enqueue(item T) error
+	// Dequeue removes and returns the item at the front of the backing
+	// store. It returns ErrStorageEmpty if the store is empty.
+	Dequeue() (T, error)
+	// Len returns the number of items currently stored.
+	Len() int
+	// Close releases any resources held by the backend.
+	Close() error
+}
+
+// Replayer is implemented by Storage backends that can enumerate their
+// contents without consuming them, so that a FIFO can rebuild its dirty set
+// after a restart without losing pending work.
+type Replayer[T comparable] interface {
+	// Replay returns every item currently held by the backend, in the
+	// order they would be dequeued.
+	Replay() ([]T, error)
+}
+
+// Codec marshals items to and from the bytes a durable Storage backend
+// holds on disk. MemoryStorage has no use for one: it holds items directly.
+type Codec[T any] interface {
+	Marshal(item T) ([]byte, error)
+	Unmarshal(data []byte) (T, error)
+}
+
+// JSONCodec is the default Codec, encoding items as JSON.
+type JSONCodec[T any] struct{}
+
+func (JSONCodec[T]) Marshal(item T) ([]byte, error) {
+	return json.Marshal(item)
+}
+
+func (JSONCodec[T]) Unmarshal(data []byte) (T, error) {
+	var item T
+	err := json.Unmarshal(data, &item)
+	return item, err
+}
+
+// MemoryStorage is the default Storage backend: an in-memory FIFO of items,
+// held directly with no (de)serialization. It has no durability across
+// restarts.
+type MemoryStorage[T comparable] struct {
+	mu    sync.Mutex
+	items []T
+}
+
+// NewMemoryStorage constructs an empty MemoryStorage.
+func NewMemoryStorage[T comparable]() *MemoryStorage[T] {
+	return &MemoryStorage[T]{}
+}
+
+func (s *MemoryStorage[T]) Enqueue(item T) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.items = append(s.items, item)
+	return nil
+}
+
+func (s *MemoryStorage[T]) Dequeue() (T, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if len(s.items) == 0 {
+		var noop T
+		return noop, ErrStorageEmpty
+	}
+
+	item := s.items[0]
+	var zero T
+	s.items[0] = zero
+	s.items = s.items[1:]
+	return item, nil
+}
+
+func (s *MemoryStorage[T]) Len() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return len(s.items)
+}
+
+func (s *MemoryStorage[T]) Close() error {
+	return nil
+}